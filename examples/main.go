@@ -20,26 +20,52 @@ func main() {
 	rollingWindowLimiter := rate_limiting_strategies.NewSlidingWindowLimiter(client, time.Now)
 	tokenBucketLimiter := rate_limiting_strategies.NewTokenBucketLimiter(client, time.Now, 10, time.Minute, 5)
 
+	extractor := rate_limiter_impl.NewHttpHeaderExtractor("X-Client-ID")
+
 	// Rate limiter configs
 	fixedWindowConfig := &rate_limiter_impl.RateLimiterConfig{
-		Extractor:   rate_limiter_impl.NewHttpHeaderExtractor("X-Client-ID"),
-		Strategy:    fixedWindowLimiter,
-		Expiration:  time.Minute,
-		MaxRequests: 5,
+		Policy: rate_limiter_impl.Policy{
+			Rules: []rate_limiter_impl.Rule{
+				{
+					Name:         "fixed-window",
+					Extractor:    extractor,
+					Strategy:     fixedWindowLimiter,
+					StrategyName: "fixed-window",
+					Expiration:   time.Minute,
+					MaxRequests:  5,
+				},
+			},
+		},
 	}
 
 	rollingWindowConfig := &rate_limiter_impl.RateLimiterConfig{
-		Extractor:   rate_limiter_impl.NewHttpHeaderExtractor("X-Client-ID"),
-		Strategy:    rollingWindowLimiter,
-		Expiration:  time.Minute,
-		MaxRequests: 5,
+		Policy: rate_limiter_impl.Policy{
+			Rules: []rate_limiter_impl.Rule{
+				{
+					Name:         "sliding-window",
+					Extractor:    extractor,
+					Strategy:     rollingWindowLimiter,
+					StrategyName: "sliding-window",
+					Expiration:   time.Minute,
+					MaxRequests:  5,
+				},
+			},
+		},
 	}
 
 	tokenBucketConfig := &rate_limiter_impl.RateLimiterConfig{
-		Extractor:   rate_limiter_impl.NewHttpHeaderExtractor("X-Client-ID"),
-		Strategy:    tokenBucketLimiter,
-		Expiration:  time.Minute,
-		MaxRequests: 5,
+		Policy: rate_limiter_impl.Policy{
+			Rules: []rate_limiter_impl.Rule{
+				{
+					Name:         "token-bucket",
+					Extractor:    extractor,
+					Strategy:     tokenBucketLimiter,
+					StrategyName: "token-bucket",
+					Expiration:   time.Minute,
+					MaxRequests:  5,
+				},
+			},
+		},
 	}
 
 	// Define HTTP handler
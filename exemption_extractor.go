@@ -0,0 +1,137 @@
+package rate_limiter_impl
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+var _ Extractor = &exemptionExtractor{}
+
+// ErrExempt is returned by an Extractor to signal that the request should
+// skip rate limiting entirely, rather than being treated as a malformed
+// request.
+var ErrExempt = errors.New("request is exempt from rate limiting")
+
+// ExemptionOptions configures NewExemptionExtractor.
+type ExemptionOptions struct {
+	// CIDRs are client IP ranges that are always exempt.
+	CIDRs []string
+	// UserAgents are regular expressions matched against the User-Agent
+	// header; a match makes the request exempt.
+	UserAgents []string
+	// Origins are regular expressions matched against the Origin header; a
+	// match makes the request exempt.
+	Origins []string
+}
+
+type exemptionExtractor struct {
+	inner      Extractor
+	cidrs      []*net.IPNet
+	userAgents []*regexp.Regexp
+	origins    []*regexp.Regexp
+}
+
+// NewExemptionExtractor wraps an Extractor so requests matching an
+// allow-listed CIDR, user-agent, or origin bypass rate limiting instead of
+// being keyed and checked like everything else.
+func NewExemptionExtractor(inner Extractor, opts ExemptionOptions) (Extractor, error) {
+	cidrs, err := parseCIDRs(opts.CIDRs)
+	if err != nil {
+		return nil, err
+	}
+
+	userAgents, err := compileRegexes(opts.UserAgents)
+	if err != nil {
+		return nil, err
+	}
+
+	origins, err := compileRegexes(opts.Origins)
+	if err != nil {
+		return nil, err
+	}
+
+	return &exemptionExtractor{
+		inner:      inner,
+		cidrs:      cidrs,
+		userAgents: userAgents,
+		origins:    origins,
+	}, nil
+}
+
+// Extract returns ErrExempt for exempt requests, otherwise it delegates to
+// the wrapped Extractor.
+func (e *exemptionExtractor) Extract(r *http.Request) (string, error) {
+	if e.isExempt(r) {
+		return "", ErrExempt
+	}
+
+	return e.inner.Extract(r)
+}
+
+func (e *exemptionExtractor) isExempt(r *http.Request) bool {
+	if ip := net.ParseIP(remoteHost(r.RemoteAddr)); ip != nil {
+		for _, cidr := range e.cidrs {
+			if cidr.Contains(ip) {
+				return true
+			}
+		}
+	}
+
+	userAgent := r.UserAgent()
+	for _, re := range e.userAgents {
+		if re.MatchString(userAgent) {
+			return true
+		}
+	}
+
+	origin := r.Header.Get("Origin")
+	for _, re := range e.origins {
+		if re.MatchString(origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func compileRegexes(patterns []string) ([]*regexp.Regexp, error) {
+	regexes := make([]*regexp.Regexp, 0, len(patterns))
+
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		regexes = append(regexes, re)
+	}
+
+	return regexes, nil
+}
+
+type chainExtractor struct {
+	extractors []Extractor
+}
+
+// Chain combines several Extractors into one, joining their keys together,
+// so a composite bucket (e.g. IP + route) can be built from simpler parts.
+func Chain(extractors ...Extractor) Extractor {
+	return &chainExtractor{extractors: extractors}
+}
+
+// Extract runs every extractor in order and joins their keys.
+func (c *chainExtractor) Extract(r *http.Request) (string, error) {
+	values := make([]string, 0, len(c.extractors))
+
+	for _, extractor := range c.extractors {
+		value, err := extractor.Extract(r)
+		if err != nil {
+			return "", err
+		}
+		values = append(values, value)
+	}
+
+	return strings.Join(values, "-"), nil
+}
@@ -0,0 +1,97 @@
+package rate_limiter_impl
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+type stubExtractor struct {
+	key string
+	err error
+}
+
+func (s *stubExtractor) Extract(_ *http.Request) (string, error) {
+	return s.key, s.err
+}
+
+func TestExemptionExtractor_Extract(t *testing.T) {
+	extractor, err := NewExemptionExtractor(&stubExtractor{key: "inner-key"}, ExemptionOptions{
+		CIDRs:      []string{"10.0.0.0/8"},
+		UserAgents: []string{"^Pingdom.*"},
+		Origins:    []string{`^https://internal\.example\.com$`},
+	})
+	require.NoError(t, err)
+
+	tt := []struct {
+		desc       string
+		remoteAddr string
+		userAgent  string
+		origin     string
+		wantKey    string
+		wantExempt bool
+	}{
+		{
+			desc:       "delegates to the inner extractor for a non-exempt request",
+			remoteAddr: "203.0.113.5:51000",
+			wantKey:    "inner-key",
+		},
+		{
+			desc:       "is exempt for a CIDR match",
+			remoteAddr: "10.1.2.3:51000",
+			wantExempt: true,
+		},
+		{
+			desc:       "is exempt for a user-agent match",
+			remoteAddr: "203.0.113.5:51000",
+			userAgent:  "Pingdom.com_bot_version_1.4",
+			wantExempt: true,
+		},
+		{
+			desc:       "is exempt for an origin match",
+			remoteAddr: "203.0.113.5:51000",
+			origin:     "https://internal.example.com",
+			wantExempt: true,
+		},
+	}
+
+	for _, ts := range tt {
+		t.Run(ts.desc, func(t *testing.T) {
+			r := &http.Request{Header: http.Header{}, RemoteAddr: ts.remoteAddr}
+			if ts.userAgent != "" {
+				r.Header.Set("User-Agent", ts.userAgent)
+			}
+			if ts.origin != "" {
+				r.Header.Set("Origin", ts.origin)
+			}
+
+			key, err := extractor.Extract(r)
+
+			if ts.wantExempt {
+				assert.ErrorIs(t, err, ErrExempt)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, ts.wantKey, key)
+		})
+	}
+}
+
+func TestChain_Extract(t *testing.T) {
+	chain := Chain(&stubExtractor{key: "a"}, &stubExtractor{key: "b"})
+
+	key, err := chain.Extract(&http.Request{})
+	require.NoError(t, err)
+	assert.Equal(t, "a-b", key)
+}
+
+func TestChain_Extract_PropagatesError(t *testing.T) {
+	wantErr := errors.New("boom")
+	chain := Chain(&stubExtractor{key: "a"}, &stubExtractor{err: wantErr})
+
+	_, err := chain.Extract(&http.Request{})
+	assert.ErrorIs(t, err, wantErr)
+}
@@ -0,0 +1,154 @@
+package rate_limiter_impl
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+var _ Extractor = &ipExtractor{}
+
+// IPExtractorOptions configures NewIPExtractor.
+type IPExtractorOptions struct {
+	// TrustedProxies lists the CIDRs allowed to set forwarding headers
+	// (X-Forwarded-For, X-Real-IP, Forwarded). Requests arriving from any
+	// other address have their forwarding headers ignored.
+	TrustedProxies []string
+	// XFFHopsToStrip is the number of trailing hops to discard from
+	// X-Forwarded-For before picking the client IP, mirroring proxyd's
+	// stripTrailingXFF: each trusted proxy that the request passed through
+	// appends its own address to the end of the header.
+	XFFHopsToStrip int
+}
+
+type ipExtractor struct {
+	trustedProxies []*net.IPNet
+	xffHopsToStrip int
+}
+
+// NewIPExtractor creates an Extractor that keys on the client's IP address
+// rather than a spoofable request header. Forwarding headers are only
+// honoured when the immediate peer is a trusted proxy.
+func NewIPExtractor(opts IPExtractorOptions) (Extractor, error) {
+	trustedProxies, err := parseCIDRs(opts.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ipExtractor{
+		trustedProxies: trustedProxies,
+		xffHopsToStrip: opts.XFFHopsToStrip,
+	}, nil
+}
+
+// Extract returns the client IP for the request.
+func (e *ipExtractor) Extract(r *http.Request) (string, error) {
+	ip := e.clientIP(r)
+	if ip == "" {
+		return "", fmt.Errorf("unable to determine client IP from request")
+	}
+
+	return ip, nil
+}
+
+func (e *ipExtractor) clientIP(r *http.Request) string {
+	remoteIP := remoteHost(r.RemoteAddr)
+
+	if !e.isTrustedProxy(remoteIP) {
+		return remoteIP
+	}
+
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		return stripTrailingXFF(xff, e.xffHopsToStrip)
+	}
+
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); realIP != "" {
+		return realIP
+	}
+
+	if forwarded := strings.TrimSpace(r.Header.Get("Forwarded")); forwarded != "" {
+		if ip := parseForwardedFor(forwarded); ip != "" {
+			return ip
+		}
+	}
+
+	return remoteIP
+}
+
+func (e *ipExtractor) isTrustedProxy(ip string) bool {
+	if len(e.trustedProxies) == 0 || ip == "" {
+		return false
+	}
+
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+
+	for _, proxy := range e.trustedProxies {
+		if proxy.Contains(parsed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// stripTrailingXFF returns the right-most address in an X-Forwarded-For
+// header after discarding hopsToStrip trusted-proxy entries from the end.
+func stripTrailingXFF(header string, hopsToStrip int) string {
+	parts := strings.Split(header, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+
+	idx := len(parts) - 1 - hopsToStrip
+	if idx < 0 {
+		idx = 0
+	}
+
+	return parts[idx]
+}
+
+// parseForwardedFor extracts the "for=" directive from a standard Forwarded
+// header (RFC 7239), taking the first entry.
+func parseForwardedFor(header string) string {
+	for _, part := range strings.Split(header, ";") {
+		part = strings.TrimSpace(part)
+		if !strings.HasPrefix(strings.ToLower(part), "for=") {
+			continue
+		}
+
+		value := strings.TrimSpace(part[len("for="):])
+		value = strings.Trim(value, `"`)
+		value = strings.Split(value, ",")[0]
+
+		return strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+	}
+
+	return ""
+}
+
+func remoteHost(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+
+	return host
+}
+
+func parseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %v: %w", cidr, err)
+		}
+		nets = append(nets, ipNet)
+	}
+
+	return nets, nil
+}
@@ -0,0 +1,84 @@
+package rate_limiter_impl
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestIPExtractor_Extract(t *testing.T) {
+	tt := []struct {
+		desc       string
+		opts       IPExtractorOptions
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		{
+			desc:       "falls back to RemoteAddr when there is no trusted proxy",
+			remoteAddr: "203.0.113.5:51000",
+			headers:    map[string]string{"X-Forwarded-For": "1.2.3.4"},
+			want:       "203.0.113.5",
+		},
+		{
+			desc: "honours X-Forwarded-For from a trusted proxy, stripping its own hop",
+			opts: IPExtractorOptions{
+				TrustedProxies: []string{"10.0.0.0/8"},
+				XFFHopsToStrip: 1,
+			},
+			remoteAddr: "10.0.0.1:51000",
+			headers:    map[string]string{"X-Forwarded-For": "1.2.3.4, 10.0.0.1"},
+			want:       "1.2.3.4",
+		},
+		{
+			desc: "only strips the configured number of trailing hops",
+			opts: IPExtractorOptions{
+				TrustedProxies: []string{"10.0.0.0/8"},
+				XFFHopsToStrip: 1,
+			},
+			remoteAddr: "10.0.0.1:51000",
+			headers:    map[string]string{"X-Forwarded-For": "1.2.3.4, 9.9.9.9, 10.0.0.1"},
+			want:       "9.9.9.9",
+		},
+		{
+			desc: "falls back to X-Real-IP when there is no X-Forwarded-For",
+			opts: IPExtractorOptions{
+				TrustedProxies: []string{"10.0.0.0/8"},
+			},
+			remoteAddr: "10.0.0.1:51000",
+			headers:    map[string]string{"X-Real-IP": "1.2.3.4"},
+			want:       "1.2.3.4",
+		},
+		{
+			desc: "ignores forwarding headers from an untrusted peer",
+			opts: IPExtractorOptions{
+				TrustedProxies: []string{"10.0.0.0/8"},
+			},
+			remoteAddr: "203.0.113.5:51000",
+			headers:    map[string]string{"X-Forwarded-For": "1.2.3.4"},
+			want:       "203.0.113.5",
+		},
+	}
+
+	for _, ts := range tt {
+		t.Run(ts.desc, func(t *testing.T) {
+			extractor, err := NewIPExtractor(ts.opts)
+			require.NoError(t, err)
+
+			r := &http.Request{Header: http.Header{}, RemoteAddr: ts.remoteAddr}
+			for key, value := range ts.headers {
+				r.Header.Set(key, value)
+			}
+
+			got, err := extractor.Extract(r)
+			require.NoError(t, err)
+			assert.Equal(t, ts.want, got)
+		})
+	}
+}
+
+func TestNewIPExtractor_InvalidCIDR(t *testing.T) {
+	_, err := NewIPExtractor(IPExtractorOptions{TrustedProxies: []string{"not-a-cidr"}})
+	assert.Error(t, err)
+}
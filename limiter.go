@@ -30,7 +30,15 @@ var stateStrings = map[State]string{
 type Result struct {
 	State         State
 	TotalRequests uint64
-	ExpiresAt     time.Time
+	// Remaining is how many further requests this key may make before the
+	// next one is denied. Unlike TotalRequests - whose meaning varies by
+	// Strategy (a used count for the window strategies, tokens left for the
+	// token bucket, always zero for GCRA) - Remaining is normalized across
+	// every Strategy, so callers such as the tightest-rule selection in
+	// httpRateLimiterHandler can compare budgets across rules without
+	// knowing which Strategy produced a Result.
+	Remaining uint64
+	ExpiresAt time.Time
 }
 
 // Strategy interface defines the contract for rate limiting strategies.
@@ -1,7 +1,9 @@
 package rate_limiter_impl
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
@@ -17,6 +19,7 @@ const (
 	rateLimitingTotalRequests = "Rate-limiting-Total-Requests"
 	rateLimitingState         = "Rate-Limiting-State"
 	rateLimitingExpiresAt     = "Rate-Limiting-Expires-At"
+	rateLimitingRule          = "Rate-Limiting-Rule"
 )
 
 // Extractor extracts a key from an HTTP request for rate limiting.
@@ -49,12 +52,54 @@ func NewHttpHeaderExtractor(headers ...string) Extractor {
 	return &httpHeaderExtractor{headers: headers}
 }
 
+// Rule is a single rate limit enforced as part of a Policy, e.g. 10 requests
+// per second or 1000 requests per day for the same or a different key.
+type Rule struct {
+	Name      string
+	Extractor Extractor
+	Strategy  Strategy
+	// StrategyName identifies the kind of Strategy in use (e.g.
+	// "fixed-window", "token-bucket", "gcra"), independent of Name, so
+	// observability can report the algorithm rather than just the rule
+	// it's enforcing. Left empty, it is reported as "unknown".
+	StrategyName string
+	Expiration   time.Duration
+	MaxRequests  uint64
+}
+
+// Policy is an ordered list of Rules that are all enforced for a single
+// request, e.g. a per-user limit plus a separate global per-IP limit.
+type Policy struct {
+	Rules []Rule
+}
+
+// RateLimitExceeded is returned when a request trips one of a Policy's
+// Rules, identifying which rule denied it.
+type RateLimitExceeded struct {
+	Rule   string
+	Result *Result
+}
+
+func (e *RateLimitExceeded) Error() string {
+	return fmt.Sprintf("rate limit exceeded for rule %q: %d requests, resets at %s",
+		e.Rule, e.Result.TotalRequests, e.Result.ExpiresAt.Format(time.RFC3339))
+}
+
 // RateLimiterConfig holds configuration for rate limiting.
 type RateLimiterConfig struct {
-	Extractor   Extractor
-	Strategy    Strategy
-	Expiration  time.Duration
-	MaxRequests uint64
+	Policy Policy
+	// Logger, if set, receives a structured event for every denied request.
+	Logger *slog.Logger
+	// OnDecision, if set, is called with the Result that decided a request:
+	// the denying rule's on a deny, otherwise the tightest allowing rule's.
+	// It is not called when every rule exempts the request or when the
+	// request fails with a 400 or 500 response. It lets callers plug in
+	// their own audit or alerting without forking.
+	OnDecision func(r *http.Request, result *Result)
+	// Metrics, if set, is recorded for every rule's Strategy execution.
+	// Regardless of whether Metrics is set, every Strategy execution also
+	// runs inside an OpenTelemetry span (see NewObservedStrategy).
+	Metrics *Metrics
 }
 
 type httpRateLimiterHandler struct {
@@ -65,39 +110,84 @@ type httpRateLimiterHandler struct {
 // NewHTTPRateLimiterHandler wraps an existing http.Handler and performs rate limiting before forwarding the
 // request to the API
 func NewHTTPRateLimiterHandler(originalHandler http.Handler, config *RateLimiterConfig) http.Handler {
+	rules := make([]Rule, len(config.Policy.Rules))
+	for i, rule := range config.Policy.Rules {
+		rule.Strategy = NewObservedStrategy(rule.StrategyName, rule.Strategy, config.Metrics)
+		rules[i] = rule
+	}
+
+	observedConfig := *config
+	observedConfig.Policy = Policy{Rules: rules}
+
 	return &httpRateLimiterHandler{
 		handler: originalHandler,
-		config:  config,
+		config:  &observedConfig,
 	}
 }
 
-// ServeHTTP performs rate limiting and forwards the request if allowed.
+// ServeHTTP evaluates every Rule in the Policy, in order, and denies the
+// request on the first one that trips. If every rule allows the request,
+// the response headers reflect whichever rule has the least remaining
+// budget.
 func (h *httpRateLimiterHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	key, err := h.config.Extractor.Extract(r)
-	if err != nil {
-		h.writeRespone(w, http.StatusBadRequest, "failed to connect rate limiting key from request: %v", err)
-		return
-	}
+	var tightestRule Rule
+	var tightestResult *Result
 
-	result, err := h.config.Strategy.Execute(r.Context(), &Request{
-		Key:      key,
-		Limit:    h.config.MaxRequests,
-		Duration: h.config.Expiration,
-	})
+	for _, rule := range h.config.Policy.Rules {
+		key, err := rule.Extractor.Extract(r)
+		if errors.Is(err, ErrExempt) {
+			continue
+		}
+		if err != nil {
+			h.writeRespone(w, http.StatusBadRequest, "failed to extract rate limiting key from request: %v", err)
+			return
+		}
+
+		result, err := rule.Strategy.Execute(withRuleName(r.Context(), rule.Name), &Request{
+			Key:      key,
+			Limit:    rule.MaxRequests,
+			Duration: rule.Expiration,
+		})
+		if err != nil {
+			h.writeRespone(w, http.StatusInternalServerError, "failed to run rate limiting for request: %v", err)
+			return
+		}
+
+		if result.State == Deny {
+			w.Header().Set(rateLimitingRule, rule.Name)
+			w.Header().Set(rateLimitingState, stateStrings[result.State])
+			w.Header().Set("Retry-After", strconv.FormatInt(int64(time.Until(result.ExpiresAt).Seconds()), 10))
+
+			if h.config.Logger != nil {
+				h.config.Logger.Warn("rate limit exceeded",
+					slog.String("key", key),
+					slog.String("rule", rule.Name),
+					slog.Time("expires_at", result.ExpiresAt))
+			}
 
-	if err != nil {
-		h.writeRespone(w, http.StatusInternalServerError, "failed to run rate limiting for request: %v", err)
-		return
+			if h.config.OnDecision != nil {
+				h.config.OnDecision(r, result)
+			}
+
+			h.writeRespone(w, http.StatusTooManyRequests, "%v", &RateLimitExceeded{Rule: rule.Name, Result: result})
+			return
+		}
+
+		if tightestResult == nil || result.Remaining < tightestResult.Remaining {
+			tightestRule = rule
+			tightestResult = result
+		}
 	}
 
-	w.Header().Set(rateLimitingTotalRequests, strconv.FormatUint(result.TotalRequests, 10))
-	w.Header().Set(rateLimitingState, stateStrings[result.State])
-	w.Header().Set(rateLimitingExpiresAt, result.ExpiresAt.Format(time.RFC3339))
+	if tightestResult != nil {
+		w.Header().Set(rateLimitingRule, tightestRule.Name)
+		w.Header().Set(rateLimitingTotalRequests, strconv.FormatUint(tightestResult.TotalRequests, 10))
+		w.Header().Set(rateLimitingState, stateStrings[tightestResult.State])
+		w.Header().Set(rateLimitingExpiresAt, tightestResult.ExpiresAt.Format(time.RFC3339))
 
-	// Too many requests
-	if result.State == Deny {
-		h.writeRespone(w, http.StatusTooManyRequests, "you have sent too many requests to this service, slow down please")
-		return
+		if h.config.OnDecision != nil {
+			h.config.OnDecision(r, tightestResult)
+		}
 	}
 
 	h.handler.ServeHTTP(w, r)
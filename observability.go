@@ -0,0 +1,145 @@
+package rate_limiter_impl
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Metrics holds the Prometheus collectors used to observe rate limiting
+// decisions across strategies.
+type Metrics struct {
+	RequestsTotal *prometheus.CounterVec
+	RedisLatency  *prometheus.HistogramVec
+	ActiveKeys    prometheus.Gauge
+}
+
+// NewMetrics creates a Metrics and registers its collectors on reg.
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		RequestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ratelimit_requests_total",
+			Help: "Total number of rate limited requests by strategy, state and rule.",
+		}, []string{"strategy", "state", "rule"}),
+		RedisLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "ratelimit_redis_latency_seconds",
+			Help: "Latency of rate limiting strategy executions.",
+		}, []string{"strategy"}),
+		ActiveKeys: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "ratelimit_active_keys",
+			Help: "Number of distinct keys currently tracked in memory by rate limiting strategies that hold their own key set (e.g. the in-memory limiter); always zero for Redis-backed strategies.",
+		}),
+	}
+
+	reg.MustRegister(m.RequestsTotal, m.RedisLatency, m.ActiveKeys)
+
+	return m
+}
+
+type ruleNameKey struct{}
+
+// withRuleName attaches the name of the Rule currently being evaluated to
+// ctx, so an observedStrategy can label its metrics without the Strategy
+// interface needing to know about rules.
+func withRuleName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, ruleNameKey{}, name)
+}
+
+func ruleNameFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(ruleNameKey{}).(string)
+	return name
+}
+
+// sizer is implemented by strategies that track a bounded set of keys in
+// memory (e.g. the in-memory limiter's TTL map), letting observedStrategy
+// report Metrics.ActiveKeys without the Strategy interface needing to know
+// about metrics.
+type sizer interface {
+	Len() int
+}
+
+var _ Strategy = &observedStrategy{}
+
+type observedStrategy struct {
+	strategyName string
+	strategy     Strategy
+	metrics      *Metrics
+	tracer       trace.Tracer
+}
+
+// NewObservedStrategy wraps a Strategy with an OpenTelemetry span and
+// Prometheus metrics around each Execute call, so any Strategy can be
+// dropped into a Policy with full observability without changing its
+// implementation. strategyName identifies the kind of Strategy being
+// wrapped (e.g. "fixed-window", "token-bucket") for the "strategy" label
+// and span attribute - it is independent of, and typically narrower than,
+// the Rule name reported via withRuleName/ruleNameFromContext.
+func NewObservedStrategy(strategyName string, strategy Strategy, metrics *Metrics) Strategy {
+	if strategyName == "" {
+		strategyName = "unknown"
+	}
+
+	return &observedStrategy{
+		strategyName: strategyName,
+		strategy:     strategy,
+		metrics:      metrics,
+		tracer:       otel.Tracer("github.com/aryangodara/rate_limiter_impl"),
+	}
+}
+
+// Execute runs the wrapped Strategy inside an OpenTelemetry span, recording
+// the limit, remaining budget and state as attributes, and reports
+// Prometheus metrics for the call.
+func (o *observedStrategy) Execute(ctx context.Context, r *Request) (*Result, error) {
+	ctx, span := o.tracer.Start(ctx, "rate_limiter.Execute", trace.WithAttributes(
+		attribute.String("strategy", o.strategyName),
+		attribute.String("key", hashKey(r.Key)),
+		attribute.Int64("limit", int64(r.Limit)),
+	))
+	defer span.End()
+
+	start := time.Now()
+	result, err := o.strategy.Execute(ctx, r)
+
+	if o.metrics != nil {
+		o.metrics.RedisLatency.WithLabelValues(o.strategyName).Observe(time.Since(start).Seconds())
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	state := "Allow"
+	if result.State == Deny {
+		state = "Deny"
+	}
+
+	span.SetAttributes(
+		attribute.Int64("remaining", int64(result.Remaining)),
+		attribute.String("state", state),
+	)
+
+	if o.metrics != nil {
+		o.metrics.RequestsTotal.WithLabelValues(o.strategyName, state, ruleNameFromContext(ctx)).Inc()
+
+		if s, ok := o.strategy.(sizer); ok {
+			o.metrics.ActiveKeys.Set(float64(s.Len()))
+		}
+	}
+
+	return result, nil
+}
+
+// hashKey returns a short, non-reversible representation of a rate limiting
+// key, so it can be attached to trace spans without leaking the raw value.
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])[:16]
+}
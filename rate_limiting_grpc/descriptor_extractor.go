@@ -0,0 +1,67 @@
+package rate_limiting_grpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	_ DescriptorExtractor = &metadataExtractor{}
+	_ DescriptorExtractor = &routeExtractor{}
+)
+
+// DescriptorExtractor extracts a key from a gRPC request for rate limiting,
+// parallel to rate_limiter_impl.Extractor for the HTTP handler.
+type DescriptorExtractor interface {
+	Extract(ctx context.Context, fullMethod string) (string, error)
+}
+
+type metadataExtractor struct {
+	keys []string
+}
+
+// Extract builds a composite key from incoming gRPC metadata.
+func (m *metadataExtractor) Extract(ctx context.Context, _ string) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", fmt.Errorf("no metadata found on incoming context")
+	}
+
+	values := make([]string, 0, len(m.keys))
+	for _, key := range m.keys {
+		vs := md.Get(key)
+		if len(vs) == 0 || strings.TrimSpace(vs[0]) == "" {
+			return "", fmt.Errorf("metadata %v must have a value set", key)
+		}
+		values = append(values, strings.TrimSpace(vs[0]))
+	}
+
+	return strings.Join(values, "-"), nil
+}
+
+// NewMetadataExtractor creates a DescriptorExtractor that builds a composite
+// key from the given incoming metadata keys.
+func NewMetadataExtractor(keys ...string) DescriptorExtractor {
+	return &metadataExtractor{keys: keys}
+}
+
+type routeExtractor struct{}
+
+// Extract uses the gRPC full method name as the key, so callers can rate
+// limit per route the same way Envoy's "route" descriptor entry does.
+func (routeExtractor) Extract(_ context.Context, fullMethod string) (string, error) {
+	if fullMethod == "" {
+		return "", fmt.Errorf("full method must have a value set")
+	}
+
+	return fullMethod, nil
+}
+
+// NewRouteExtractor creates a DescriptorExtractor keyed on the RPC's full
+// method name.
+func NewRouteExtractor() DescriptorExtractor {
+	return routeExtractor{}
+}
@@ -0,0 +1,53 @@
+package rate_limiting_grpc
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"testing"
+)
+
+func TestMetadataExtractor_Extract(t *testing.T) {
+	extractor := NewMetadataExtractor("x-user-id", "x-tenant-id")
+
+	md := metadata.New(map[string]string{
+		"x-user-id":   "42",
+		"x-tenant-id": "acme",
+	})
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+
+	key, err := extractor.Extract(ctx, "/svc/Method")
+	require.NoError(t, err)
+	assert.Equal(t, "42-acme", key)
+}
+
+func TestMetadataExtractor_Extract_MissingValue(t *testing.T) {
+	extractor := NewMetadataExtractor("x-user-id")
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.New(nil))
+
+	_, err := extractor.Extract(ctx, "/svc/Method")
+	assert.Error(t, err)
+}
+
+func TestMetadataExtractor_Extract_NoMetadata(t *testing.T) {
+	extractor := NewMetadataExtractor("x-user-id")
+
+	_, err := extractor.Extract(context.Background(), "/svc/Method")
+	assert.Error(t, err)
+}
+
+func TestRouteExtractor_Extract(t *testing.T) {
+	extractor := NewRouteExtractor()
+
+	key, err := extractor.Extract(context.Background(), "/svc/Method")
+	require.NoError(t, err)
+	assert.Equal(t, "/svc/Method", key)
+}
+
+func TestRouteExtractor_Extract_EmptyMethod(t *testing.T) {
+	extractor := NewRouteExtractor()
+
+	_, err := extractor.Extract(context.Background(), "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,70 @@
+package rate_limiting_grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/aryangodara/rate_limiter_impl"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Config holds configuration for the gRPC interceptors, mirroring
+// rate_limiter_impl.RateLimiterConfig for the HTTP handler.
+type Config struct {
+	Extractor   DescriptorExtractor
+	Strategy    rate_limiter_impl.Strategy
+	Expiration  time.Duration
+	MaxRequests uint64
+}
+
+// execute runs the configured Strategy for an incoming RPC and returns a
+// gRPC status error if the key can't be extracted or the request is denied.
+func (c *Config) execute(ctx context.Context, fullMethod string) error {
+	key, err := c.Extractor.Extract(ctx, fullMethod)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "failed to extract rate limiting key from request: %v", err)
+	}
+
+	result, err := c.Strategy.Execute(ctx, &rate_limiter_impl.Request{
+		Key:      key,
+		Limit:    c.MaxRequests,
+		Duration: c.Expiration,
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to run rate limiting for request: %v", err)
+	}
+
+	if result.State == rate_limiter_impl.Deny {
+		return status.Errorf(codes.ResourceExhausted, "you have sent too many requests to this service, slow down please")
+	}
+
+	return nil
+}
+
+// NewUnaryServerInterceptor creates a grpc.UnaryServerInterceptor that rate
+// limits unary RPCs before forwarding them to the handler, mirroring
+// NewHTTPRateLimiterHandler.
+func NewUnaryServerInterceptor(config *Config) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := config.execute(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// NewStreamServerInterceptor creates a grpc.StreamServerInterceptor that rate
+// limits streaming RPCs before forwarding them to the handler, mirroring
+// NewHTTPRateLimiterHandler.
+func NewStreamServerInterceptor(config *Config) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := config.execute(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,98 @@
+package rate_limiting_grpc
+
+import (
+	"context"
+	"github.com/aryangodara/rate_limiter_impl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+type stubStrategy struct {
+	result *rate_limiter_impl.Result
+	err    error
+}
+
+func (s *stubStrategy) Execute(_ context.Context, _ *rate_limiter_impl.Request) (*rate_limiter_impl.Result, error) {
+	return s.result, s.err
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (f *fakeServerStream) Context() context.Context {
+	return f.ctx
+}
+
+func TestNewUnaryServerInterceptor(t *testing.T) {
+	tt := []struct {
+		desc     string
+		strategy *stubStrategy
+		wantCode codes.Code
+	}{
+		{
+			desc:     "forwards the call when the strategy allows it",
+			strategy: &stubStrategy{result: &rate_limiter_impl.Result{State: rate_limiter_impl.Allow}},
+			wantCode: codes.OK,
+		},
+		{
+			desc:     "denies the call when the strategy denies it",
+			strategy: &stubStrategy{result: &rate_limiter_impl.Result{State: rate_limiter_impl.Deny}},
+			wantCode: codes.ResourceExhausted,
+		},
+	}
+
+	for _, ts := range tt {
+		t.Run(ts.desc, func(t *testing.T) {
+			interceptor := NewUnaryServerInterceptor(&Config{
+				Extractor:   NewRouteExtractor(),
+				Strategy:    ts.strategy,
+				Expiration:  time.Minute,
+				MaxRequests: 10,
+			})
+
+			called := false
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				return "ok", nil
+			}
+
+			resp, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/svc/Method"}, handler)
+
+			assert.Equal(t, ts.wantCode, status.Code(err))
+			if ts.wantCode == codes.OK {
+				require.NoError(t, err)
+				assert.True(t, called)
+				assert.Equal(t, "ok", resp)
+			} else {
+				assert.False(t, called)
+			}
+		})
+	}
+}
+
+func TestNewStreamServerInterceptor_Denies(t *testing.T) {
+	interceptor := NewStreamServerInterceptor(&Config{
+		Extractor:   NewRouteExtractor(),
+		Strategy:    &stubStrategy{result: &rate_limiter_impl.Result{State: rate_limiter_impl.Deny}},
+		Expiration:  time.Minute,
+		MaxRequests: 10,
+	})
+
+	called := false
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		called = true
+		return nil
+	}
+
+	err := interceptor(nil, &fakeServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/svc/Method"}, handler)
+
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.False(t, called)
+}
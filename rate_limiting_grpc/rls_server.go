@@ -0,0 +1,144 @@
+package rate_limiting_grpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aryangodara/rate_limiter_impl"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	rlsv3 "github.com/envoyproxy/go-control-plane/envoy/service/ratelimit/v3"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+var _ rlsv3.RateLimitServiceServer = &rateLimitServiceServer{}
+
+// DescriptorKey identifies a single Envoy rate limit descriptor entry, e.g.
+// {Key: "user_id", Value: "x"} or {Key: "route", Value: "/api"}.
+type DescriptorKey struct {
+	Key   string
+	Value string
+}
+
+// Rule is the (MaxRequests, Expiration, Strategy) tuple applied to requests
+// matching a DescriptorKey.
+type Rule struct {
+	Strategy    rate_limiter_impl.Strategy
+	MaxRequests uint64
+	Expiration  time.Duration
+}
+
+// RLSConfig maps descriptors to independent rate limiting rules, so e.g.
+// ("user_id", "x") and ("route", "/api") get independent buckets.
+type RLSConfig struct {
+	Rules map[DescriptorKey]Rule
+}
+
+type rateLimitServiceServer struct {
+	rlsv3.UnimplementedRateLimitServiceServer
+	config *RLSConfig
+}
+
+// NewRateLimitServiceServer creates an Envoy-compatible RLS server backed by
+// the existing Strategy implementations, so this module can be dropped in as
+// an external rate limit service for Envoy/Istio.
+func NewRateLimitServiceServer(config *RLSConfig) rlsv3.RateLimitServiceServer {
+	return &rateLimitServiceServer{config: config}
+}
+
+// ShouldRateLimit implements envoy.service.ratelimit.v3.RateLimitService.
+func (s *rateLimitServiceServer) ShouldRateLimit(ctx context.Context, req *rlsv3.RateLimitRequest) (*rlsv3.RateLimitResponse, error) {
+	statuses := make([]*rlsv3.RateLimitResponse_DescriptorStatus, 0, len(req.Descriptors))
+	overallCode := rlsv3.RateLimitResponse_OK
+	var headerResult *rate_limiter_impl.Result
+
+	for _, descriptor := range req.Descriptors {
+		rule, ok := s.ruleFor(descriptor)
+		if !ok {
+			statuses = append(statuses, &rlsv3.RateLimitResponse_DescriptorStatus{Code: rlsv3.RateLimitResponse_OK})
+			continue
+		}
+
+		key, err := descriptorKeyString(descriptor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build rate limiting key for descriptor: %w", err)
+		}
+
+		result, err := rule.Strategy.Execute(ctx, &rate_limiter_impl.Request{
+			Key:      key,
+			Limit:    rule.MaxRequests,
+			Duration: rule.Expiration,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to run rate limiting for descriptor: %w", err)
+		}
+
+		code := rlsv3.RateLimitResponse_OK
+		if result.State == rate_limiter_impl.Deny {
+			code = rlsv3.RateLimitResponse_OVER_LIMIT
+			overallCode = rlsv3.RateLimitResponse_OVER_LIMIT
+		}
+
+		if headerResult == nil || result.State == rate_limiter_impl.Deny {
+			headerResult = result
+		}
+
+		statuses = append(statuses, &rlsv3.RateLimitResponse_DescriptorStatus{
+			Code:               code,
+			CurrentLimit:       &rlsv3.RateLimitResponse_RateLimit{RequestsPerUnit: uint32(rule.MaxRequests)},
+			LimitRemaining:     uint32(result.Remaining),
+			DurationUntilReset: durationpb.New(time.Until(result.ExpiresAt)),
+		})
+	}
+
+	return &rlsv3.RateLimitResponse{
+		OverallCode:          overallCode,
+		Statuses:             statuses,
+		ResponseHeadersToAdd: responseHeaders(headerResult),
+	}, nil
+}
+
+func (s *rateLimitServiceServer) ruleFor(descriptor *rlsv3.RateLimitDescriptor) (Rule, bool) {
+	for _, entry := range descriptor.Entries {
+		if rule, ok := s.config.Rules[DescriptorKey{Key: entry.Key, Value: entry.Value}]; ok {
+			return rule, true
+		}
+	}
+
+	return Rule{}, false
+}
+
+func descriptorKeyString(descriptor *rlsv3.RateLimitDescriptor) (string, error) {
+	if len(descriptor.Entries) == 0 {
+		return "", fmt.Errorf("descriptor has no entries")
+	}
+
+	parts := make([]string, 0, len(descriptor.Entries))
+	for _, entry := range descriptor.Entries {
+		parts = append(parts, entry.Key+":"+entry.Value)
+	}
+
+	return strings.Join(parts, "-"), nil
+}
+
+// responseHeaders sets the Rate-Limiting-Total-Requests, Rate-Limiting-State
+// and Rate-Limiting-Expires-At headers the HTTP handler sets on an allowed
+// request, so callers behind Envoy see the same values regardless of
+// transport. Rate-Limiting-Rule and Retry-After are not set.
+func responseHeaders(result *rate_limiter_impl.Result) []*corev3.HeaderValueOption {
+	if result == nil {
+		return nil
+	}
+
+	state := "Allow"
+	if result.State == rate_limiter_impl.Deny {
+		state = "Deny"
+	}
+
+	return []*corev3.HeaderValueOption{
+		{Header: &corev3.HeaderValue{Key: "Rate-limiting-Total-Requests", Value: fmt.Sprintf("%d", result.TotalRequests)}},
+		{Header: &corev3.HeaderValue{Key: "Rate-Limiting-State", Value: state}},
+		{Header: &corev3.HeaderValue{Key: "Rate-Limiting-Expires-At", Value: result.ExpiresAt.Format(time.RFC3339)}},
+	}
+}
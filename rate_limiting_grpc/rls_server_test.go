@@ -0,0 +1,161 @@
+package rate_limiting_grpc
+
+import (
+	"context"
+	"github.com/aryangodara/rate_limiter_impl"
+	rlsv3 "github.com/envoyproxy/go-control-plane/envoy/service/ratelimit/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func descriptor(key, value string) *rlsv3.RateLimitDescriptor {
+	return &rlsv3.RateLimitDescriptor{
+		Entries: []*rlsv3.RateLimitDescriptor_Entry{{Key: key, Value: value}},
+	}
+}
+
+func TestRateLimitServiceServer_ShouldRateLimit(t *testing.T) {
+	allowResult := &rate_limiter_impl.Result{
+		State:     rate_limiter_impl.Allow,
+		Remaining: 7,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+	denyResult := &rate_limiter_impl.Result{
+		State:     rate_limiter_impl.Deny,
+		Remaining: 0,
+		ExpiresAt: time.Now().Add(30 * time.Second),
+	}
+
+	config := &RLSConfig{
+		Rules: map[DescriptorKey]Rule{
+			{Key: "user_id", Value: "allowed-user"}: {
+				Strategy:    &stubStrategy{result: allowResult},
+				MaxRequests: 10,
+				Expiration:  time.Minute,
+			},
+			{Key: "user_id", Value: "denied-user"}: {
+				Strategy:    &stubStrategy{result: denyResult},
+				MaxRequests: 10,
+				Expiration:  time.Minute,
+			},
+		},
+	}
+
+	server := NewRateLimitServiceServer(config)
+
+	tt := []struct {
+		desc          string
+		descriptors   []*rlsv3.RateLimitDescriptor
+		wantOverall   rlsv3.RateLimitResponse_Code
+		wantCodes     []rlsv3.RateLimitResponse_Code
+		wantRemaining []uint32
+		wantResets    []*time.Time
+	}{
+		{
+			desc:          "allows a descriptor under limit",
+			descriptors:   []*rlsv3.RateLimitDescriptor{descriptor("user_id", "allowed-user")},
+			wantOverall:   rlsv3.RateLimitResponse_OK,
+			wantCodes:     []rlsv3.RateLimitResponse_Code{rlsv3.RateLimitResponse_OK},
+			wantRemaining: []uint32{7},
+			wantResets:    []*time.Time{&allowResult.ExpiresAt},
+		},
+		{
+			desc:          "denies a descriptor over limit",
+			descriptors:   []*rlsv3.RateLimitDescriptor{descriptor("user_id", "denied-user")},
+			wantOverall:   rlsv3.RateLimitResponse_OVER_LIMIT,
+			wantCodes:     []rlsv3.RateLimitResponse_Code{rlsv3.RateLimitResponse_OVER_LIMIT},
+			wantRemaining: []uint32{0},
+			wantResets:    []*time.Time{&denyResult.ExpiresAt},
+		},
+		{
+			desc: "rolls the overall code up to OVER_LIMIT when any descriptor is denied",
+			descriptors: []*rlsv3.RateLimitDescriptor{
+				descriptor("user_id", "allowed-user"),
+				descriptor("user_id", "denied-user"),
+			},
+			wantOverall:   rlsv3.RateLimitResponse_OVER_LIMIT,
+			wantCodes:     []rlsv3.RateLimitResponse_Code{rlsv3.RateLimitResponse_OK, rlsv3.RateLimitResponse_OVER_LIMIT},
+			wantRemaining: []uint32{7, 0},
+			wantResets:    []*time.Time{&allowResult.ExpiresAt, &denyResult.ExpiresAt},
+		},
+		{
+			desc:          "treats a descriptor with no matching rule as OK",
+			descriptors:   []*rlsv3.RateLimitDescriptor{descriptor("user_id", "unknown-user")},
+			wantOverall:   rlsv3.RateLimitResponse_OK,
+			wantCodes:     []rlsv3.RateLimitResponse_Code{rlsv3.RateLimitResponse_OK},
+			wantRemaining: []uint32{0},
+			wantResets:    []*time.Time{nil},
+		},
+	}
+
+	for _, ts := range tt {
+		t.Run(ts.desc, func(t *testing.T) {
+			resp, err := server.ShouldRateLimit(context.Background(), &rlsv3.RateLimitRequest{Descriptors: ts.descriptors})
+			require.NoError(t, err)
+
+			assert.Equal(t, ts.wantOverall, resp.OverallCode)
+			require.Len(t, resp.Statuses, len(ts.wantCodes))
+
+			for i, status := range resp.Statuses {
+				assert.Equal(t, ts.wantCodes[i], status.Code)
+				assert.Equal(t, ts.wantRemaining[i], status.LimitRemaining)
+
+				if ts.wantResets[i] == nil {
+					assert.Nil(t, status.DurationUntilReset)
+					continue
+				}
+
+				require.NotNil(t, status.DurationUntilReset)
+				wantSeconds := time.Until(*ts.wantResets[i]).Seconds()
+				assert.InDelta(t, wantSeconds, status.DurationUntilReset.AsDuration().Seconds(), 2)
+			}
+		})
+	}
+}
+
+func TestRateLimitServiceServer_ShouldRateLimit_ResponseHeadersReflectDeniedDescriptor(t *testing.T) {
+	allowResult := &rate_limiter_impl.Result{State: rate_limiter_impl.Allow, Remaining: 5, ExpiresAt: time.Now().Add(time.Minute)}
+	denyResult := &rate_limiter_impl.Result{State: rate_limiter_impl.Deny, Remaining: 0, ExpiresAt: time.Now().Add(30 * time.Second)}
+
+	config := &RLSConfig{
+		Rules: map[DescriptorKey]Rule{
+			{Key: "user_id", Value: "allowed-user"}: {Strategy: &stubStrategy{result: allowResult}, MaxRequests: 10, Expiration: time.Minute},
+			{Key: "user_id", Value: "denied-user"}:  {Strategy: &stubStrategy{result: denyResult}, MaxRequests: 10, Expiration: time.Minute},
+		},
+	}
+
+	server := NewRateLimitServiceServer(config)
+
+	resp, err := server.ShouldRateLimit(context.Background(), &rlsv3.RateLimitRequest{
+		Descriptors: []*rlsv3.RateLimitDescriptor{
+			descriptor("user_id", "allowed-user"),
+			descriptor("user_id", "denied-user"),
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, resp.ResponseHeadersToAdd, 3)
+	headers := map[string]string{}
+	for _, h := range resp.ResponseHeadersToAdd {
+		headers[h.Header.Key] = h.Header.Value
+	}
+	assert.Equal(t, "Deny", headers["Rate-Limiting-State"])
+}
+
+func TestRateLimitServiceServer_ShouldRateLimit_PropagatesStrategyError(t *testing.T) {
+	wantErr := assert.AnError
+	config := &RLSConfig{
+		Rules: map[DescriptorKey]Rule{
+			{Key: "user_id", Value: "errors"}: {Strategy: &stubStrategy{err: wantErr}, MaxRequests: 10, Expiration: time.Minute},
+		},
+	}
+
+	server := NewRateLimitServiceServer(config)
+
+	_, err := server.ShouldRateLimit(context.Background(), &rlsv3.RateLimitRequest{
+		Descriptors: []*rlsv3.RateLimitDescriptor{descriptor("user_id", "errors")},
+	})
+	assert.Error(t, err)
+}
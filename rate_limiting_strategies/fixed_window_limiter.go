@@ -2,7 +2,6 @@ package rate_limiting_strategies
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"github.com/aryangodara/rate_limiter_impl"
 	"github.com/redis/go-redis/v9"
@@ -13,10 +12,19 @@ var (
 	_ rate_limiter_impl.Strategy = &fixedWindowLimiter{}
 )
 
-const (
-	keyDNE      = -2
-	keyNoExpire = -1
-)
+// fixedWindowScript atomically increments the request counter for a key and
+// makes sure it always carries a TTL, so the INCR, the TTL check and the
+// conditional PEXPIRE happen in a single round trip instead of the
+// GET -> TTL -> INCR sequence this used to run.
+var fixedWindowScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl < 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+	ttl = tonumber(ARGV[1])
+end
+return {count, ttl}
+`)
 
 type fixedWindowLimiter struct {
 	client *redis.Client
@@ -33,47 +41,39 @@ func NewFixedWindowLimiter(client *redis.Client, now func() time.Time) rate_limi
 
 // Execute performs rate limiting using a fixed window strategy.
 func (f *fixedWindowLimiter) Execute(ctx context.Context, r *rate_limiter_impl.Request) (*rate_limiter_impl.Result, error) {
-	// Redis pipeline to optimize network round trips.
-	pipe := f.client.Pipeline()
-	getCmd := pipe.Get(ctx, r.Key)
-	ttlCmd := pipe.TTL(ctx, r.Key)
-
-	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
-		return nil, fmt.Errorf("error executing Redis pipeline for key %v: %w", r.Key, err)
+	res, err := fixedWindowScript.Run(ctx, f.client, []string{r.Key}, r.Duration.Milliseconds()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error executing fixed window script for key %v: %w", r.Key, err)
 	}
 
-	var ttl time.Duration
-
-	if duration, err := ttlCmd.Result(); err != nil || duration == keyDNE || duration == keyNoExpire {
-		ttl = r.Duration
-		if err := f.client.Expire(ctx, r.Key, r.Duration).Err(); err != nil {
-			return nil, fmt.Errorf("error setting expiration for key %v: %w", r.Key, err)
-		}
-	} else {
-		ttl = duration
+	values, ok := res.([]interface{})
+	if !ok || len(values) != 2 {
+		return nil, fmt.Errorf("unexpected result from fixed window script for key %v: %v", r.Key, res)
 	}
 
-	expirationTime := f.now().Add(ttl)
+	count, ok := values[0].(int64)
+	if !ok {
+		return nil, fmt.Errorf("unexpected count type from fixed window script for key %v: %v", r.Key, values[0])
+	}
 
-	if count, err := getCmd.Uint64(); err != nil && errors.Is(err, redis.Nil) {
-	} else if count >= r.Limit {
-		return &rate_limiter_impl.Result{
-			State:         rate_limiter_impl.Deny,
-			TotalRequests: count,
-			ExpiresAt:     expirationTime,
-		}, nil
+	ttlMs, ok := values[1].(int64)
+	if !ok {
+		return nil, fmt.Errorf("unexpected ttl type from fixed window script for key %v: %v", r.Key, values[1])
 	}
 
-	incrementCmd := f.client.Incr(ctx, r.Key)
-	requestCount, err := incrementCmd.Uint64()
-	if err != nil {
-		return nil, fmt.Errorf("error incrementing key %v: %w", r.Key, err)
+	expirationTime := f.now().Add(time.Duration(ttlMs) * time.Millisecond)
+	requestCount := uint64(count)
+
+	var remaining uint64
+	if requestCount < r.Limit {
+		remaining = r.Limit - requestCount
 	}
 
 	if requestCount > r.Limit {
 		return &rate_limiter_impl.Result{
 			State:         rate_limiter_impl.Deny,
 			TotalRequests: requestCount,
+			Remaining:     remaining,
 			ExpiresAt:     expirationTime,
 		}, nil
 	}
@@ -81,6 +81,7 @@ func (f *fixedWindowLimiter) Execute(ctx context.Context, r *rate_limiter_impl.R
 	return &rate_limiter_impl.Result{
 		State:         rate_limiter_impl.Allow,
 		TotalRequests: requestCount,
+		Remaining:     remaining,
 		ExpiresAt:     expirationTime,
 	}, nil
 }
@@ -0,0 +1,84 @@
+package rate_limiting_strategies
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/aryangodara/rate_limiter_impl"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestGCRALimiter_Execute(t *testing.T) {
+	tt := []struct {
+		desc  string
+		runs  int64
+		burst int64
+		req   *rate_limiter_impl.Request
+		res   *rate_limiter_impl.Result
+	}{
+		{
+			desc:  "allows a request within the burst",
+			burst: 5,
+			req: &rate_limiter_impl.Request{
+				Key:      "some-user",
+				Limit:    60,
+				Duration: time.Minute,
+			},
+			res: &rate_limiter_impl.Result{
+				State:         rate_limiter_impl.Allow,
+				TotalRequests: 0,
+				Remaining:     4,
+				ExpiresAt:     time.Date(2024, time.June, 23, 10, 15, 31, 0, time.Local),
+			},
+			runs: 1,
+		},
+		{
+			desc:  "denies once the burst is exhausted",
+			burst: 5,
+			req: &rate_limiter_impl.Request{
+				Key:      "some-user",
+				Limit:    60,
+				Duration: time.Minute,
+			},
+			res: &rate_limiter_impl.Result{
+				State:         rate_limiter_impl.Deny,
+				TotalRequests: 0,
+				Remaining:     0,
+				ExpiresAt:     time.Date(2024, time.June, 23, 10, 15, 31, 0, time.Local),
+			},
+			runs: 6,
+		},
+	}
+
+	for _, ts := range tt {
+		t.Run(ts.desc, func(t *testing.T) {
+			server, err := miniredis.Run()
+			require.NoError(t, err)
+			defer server.Close()
+
+			now := time.Date(2024, time.June, 23, 10, 15, 30, 0, time.Local)
+
+			client := redis.NewClient(&redis.Options{
+				Addr: server.Addr(),
+			})
+			defer client.Close()
+
+			limiter := NewGCRALimiter(client, func() time.Time {
+				return now
+			}, ts.burst)
+
+			var lastRes *rate_limiter_impl.Result
+			var lastErr error
+
+			for x := int64(0); x < ts.runs; x++ {
+				lastRes, lastErr = limiter.Execute(context.Background(), ts.req)
+			}
+
+			require.NoError(t, lastErr)
+			assert.Equal(t, ts.res, lastRes)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package rate_limiting_strategies
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aryangodara/rate_limiter_impl"
+	"golang.org/x/time/rate"
+)
+
+var (
+	_ rate_limiter_impl.Strategy = &inMemoryLimiter{}
+)
+
+// maxInMemorySources bounds how many distinct keys the limiter tracks at
+// once, so a flood of one-off keys can't grow the map without limit.
+const maxInMemorySources = 65536
+
+type inMemoryEntry struct {
+	limiter     *rate.Limiter
+	duration    time.Duration
+	count       uint64
+	windowStart time.Time
+}
+
+type inMemoryListItem struct {
+	key   string
+	entry *inMemoryEntry
+}
+
+type inMemoryLimiter struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	entries map[string]*list.Element
+	order   *list.List
+	maxSize int
+}
+
+// NewInMemoryLimiter creates a rate limiter that needs no Redis, keyed by the
+// extracted key and backed by a golang.org/x/time/rate.Limiter per key. Each
+// key's window resets once its Expiration has elapsed since the window
+// started, and the least-recently-used key is evicted once the number of
+// tracked keys reaches maxInMemorySources.
+func NewInMemoryLimiter(now func() time.Time) rate_limiter_impl.Strategy {
+	return &inMemoryLimiter{
+		now:     now,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		maxSize: maxInMemorySources,
+	}
+}
+
+// Execute performs rate limiting using an in-memory token bucket per key.
+func (l *inMemoryLimiter) Execute(_ context.Context, r *rate_limiter_impl.Request) (*rate_limiter_impl.Result, error) {
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	elem, ok := l.entries[r.Key]
+
+	var item *inMemoryListItem
+	if ok {
+		item = elem.Value.(*inMemoryListItem)
+		if now.Sub(item.entry.windowStart) >= item.entry.duration {
+			// current window (and any idle gap past it) has elapsed:
+			// start a fresh one
+			item.entry = newInMemoryEntry(r, now)
+		}
+		l.order.MoveToFront(elem)
+	} else {
+		if l.order.Len() >= l.maxSize {
+			l.evictOldest()
+		}
+
+		item = &inMemoryListItem{key: r.Key, entry: newInMemoryEntry(r, now)}
+		l.entries[r.Key] = l.order.PushFront(item)
+	}
+
+	item.entry.count++
+
+	state := rate_limiter_impl.Allow
+	if !item.entry.limiter.AllowN(now, 1) {
+		state = rate_limiter_impl.Deny
+	}
+
+	var remaining uint64
+	if item.entry.count < r.Limit {
+		remaining = r.Limit - item.entry.count
+	}
+
+	return &rate_limiter_impl.Result{
+		State:         state,
+		TotalRequests: item.entry.count,
+		Remaining:     remaining,
+		ExpiresAt:     item.entry.windowStart.Add(item.entry.duration),
+	}, nil
+}
+
+func newInMemoryEntry(r *rate_limiter_impl.Request, now time.Time) *inMemoryEntry {
+	eventsPerSecond := float64(r.Limit) / r.Duration.Seconds()
+
+	return &inMemoryEntry{
+		limiter:     rate.NewLimiter(rate.Limit(eventsPerSecond), int(r.Limit)),
+		duration:    r.Duration,
+		windowStart: now,
+	}
+}
+
+// Len returns the number of distinct keys currently tracked.
+func (l *inMemoryLimiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return len(l.entries)
+}
+
+func (l *inMemoryLimiter) evictOldest() {
+	oldest := l.order.Back()
+	if oldest == nil {
+		return
+	}
+
+	item := oldest.Value.(*inMemoryListItem)
+	delete(l.entries, item.key)
+	l.order.Remove(oldest)
+}
@@ -0,0 +1,115 @@
+package rate_limiting_strategies
+
+import (
+	"context"
+	"github.com/aryangodara/rate_limiter_impl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestInMemoryLimiter_Execute(t *testing.T) {
+	tt := []struct {
+		desc        string
+		runs        int64
+		req         *rate_limiter_impl.Request
+		res         *rate_limiter_impl.Result
+		timeAdvance time.Duration
+	}{
+		{
+			desc: "returns Allow for requests under limit",
+			req: &rate_limiter_impl.Request{
+				Key:      "some-user",
+				Limit:    10,
+				Duration: time.Minute,
+			},
+			res: &rate_limiter_impl.Result{
+				State:         rate_limiter_impl.Allow,
+				TotalRequests: 5,
+				Remaining:     5,
+				ExpiresAt:     time.Date(2024, time.June, 23, 10, 16, 30, 0, time.Local),
+			},
+			runs: 5,
+		},
+		{
+			desc: "returns Deny once the burst is exhausted",
+			req: &rate_limiter_impl.Request{
+				Key:      "some-user",
+				Limit:    10,
+				Duration: time.Minute,
+			},
+			res: &rate_limiter_impl.Result{
+				State:         rate_limiter_impl.Deny,
+				TotalRequests: 11,
+				Remaining:     0,
+				ExpiresAt:     time.Date(2024, time.June, 23, 10, 16, 30, 0, time.Local),
+			},
+			runs: 11,
+		},
+		{
+			desc: "resets the count once the window elapses",
+			req: &rate_limiter_impl.Request{
+				Key:      "some-user",
+				Limit:    10,
+				Duration: time.Minute,
+			},
+			res: &rate_limiter_impl.Result{
+				State:         rate_limiter_impl.Allow,
+				TotalRequests: 1,
+				Remaining:     9,
+				ExpiresAt:     time.Date(2024, time.June, 23, 10, 25, 30, 0, time.Local),
+			},
+			runs:        10,
+			timeAdvance: time.Minute,
+		},
+	}
+
+	for _, ts := range tt {
+		t.Run(ts.desc, func(t *testing.T) {
+			now := time.Date(2024, time.June, 23, 10, 15, 30, 0, time.Local)
+
+			limiter := NewInMemoryLimiter(func() time.Time {
+				return now
+			})
+
+			var lastRes *rate_limiter_impl.Result
+			var lastErr error
+
+			for x := int64(0); x < ts.runs; x++ {
+				lastRes, lastErr = limiter.Execute(context.Background(), ts.req)
+				if ts.timeAdvance != 0 {
+					now = now.Add(ts.timeAdvance)
+				}
+			}
+
+			require.NoError(t, lastErr)
+			assert.Equal(t, ts.res, lastRes)
+		})
+	}
+}
+
+func TestInMemoryLimiter_EvictsLeastRecentlyUsed(t *testing.T) {
+	now := time.Date(2024, time.June, 23, 10, 15, 30, 0, time.Local)
+
+	limiter := NewInMemoryLimiter(func() time.Time {
+		return now
+	}).(*inMemoryLimiter)
+	limiter.maxSize = 2
+
+	req := func(key string) *rate_limiter_impl.Request {
+		return &rate_limiter_impl.Request{Key: key, Limit: 10, Duration: time.Minute}
+	}
+
+	_, err := limiter.Execute(context.Background(), req("a"))
+	require.NoError(t, err)
+	_, err = limiter.Execute(context.Background(), req("b"))
+	require.NoError(t, err)
+	// "a" is now the least recently used of the two tracked keys.
+	_, err = limiter.Execute(context.Background(), req("c"))
+	require.NoError(t, err)
+
+	assert.Len(t, limiter.entries, 2)
+	_, aTracked := limiter.entries["a"]
+	assert.False(t, aTracked, "least recently used key should have been evicted")
+}
@@ -6,7 +6,6 @@ import (
 	"github.com/aryangodara/rate_limiter_impl"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
-	"strconv"
 	"time"
 )
 
@@ -14,10 +13,33 @@ var (
 	_ rate_limiter_impl.Strategy = &slidingWindowLimiter{}
 )
 
-const (
-	maxSortedSetScore = "+inf"
-	minSortedSetScore = "-inf"
-)
+// slidingWindowScript atomically trims expired entries, counts what is left,
+// and admits the request only if it still fits under the limit, so
+// ZREMRANGEBYSCORE, ZCARD, the conditional ZADD and PEXPIRE all run as a
+// single round trip instead of the ZCOUNT + pipeline this used to take.
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local windowStart = ARGV[1]
+local now = tonumber(ARGV[2])
+local member = ARGV[3]
+local limit = tonumber(ARGV[4])
+local expireMs = ARGV[5]
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', windowStart)
+
+local count = redis.call('ZCARD', key)
+local allowed = 0
+
+if count < limit then
+	redis.call('ZADD', key, now, member)
+	count = count + 1
+	allowed = 1
+end
+
+redis.call('PEXPIRE', key, expireMs)
+
+return {allowed, count}
+`)
 
 type slidingWindowLimiter struct {
 	client *redis.Client
@@ -36,57 +58,45 @@ func NewSlidingWindowLimiter(client *redis.Client, now func() time.Time) rate_li
 func (s *slidingWindowLimiter) Execute(ctx context.Context, r *rate_limiter_impl.Request) (*rate_limiter_impl.Result, error) {
 	now := s.now()
 	expiresAt := now.Add(r.Duration)
-	minimum := now.Add(-r.Duration)
-
-	result, err := s.client.ZCount(ctx, r.Key, strconv.FormatInt(minimum.UnixMilli(), 10), maxSortedSetScore).Uint64()
-	if err == nil && result >= r.Limit {
-		return &rate_limiter_impl.Result{
-			State:         rate_limiter_impl.Deny,
-			TotalRequests: result,
-			ExpiresAt:     expiresAt,
-		}, nil
-	}
-
-	// every request needs an UUID
-	item := uuid.New()
-
-	p := s.client.Pipeline()
-
-	// we then remove all the expired requests
-	removeByScore := p.ZRemRangeByScore(ctx, r.Key, "0", strconv.FormatInt(minimum.UnixMilli(), 10))
+	windowStart := now.Add(-r.Duration)
 
-	// we add the current request
-	add := p.ZAdd(ctx, r.Key, redis.Z{
-		Score:  float64(now.UnixMilli()),
-		Member: item.String(),
-	})
+	// every request needs a unique member so it can sit in the sorted set
+	// alongside requests that landed in the same millisecond
+	member := uuid.New().String()
 
-	// count how many non-expired requests we have on the sorted set
-	count := p.ZCount(ctx, r.Key, minSortedSetScore, maxSortedSetScore)
-
-	if _, err := p.Exec(ctx); err != nil {
-		return nil, fmt.Errorf("failed to execute sorted set pipeline for key: %v: %w", r.Key, err)
+	res, err := slidingWindowScript.Run(ctx, s.client, []string{r.Key},
+		windowStart.UnixMilli(), now.UnixMilli(), member, r.Limit, r.Duration.Milliseconds()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error executing sliding window script for key %v: %w", r.Key, err)
 	}
 
-	if err := removeByScore.Err(); err != nil {
-		return nil, fmt.Errorf("failed to remove old requests from key %v: %w", r.Key, err)
+	values, ok := res.([]interface{})
+	if !ok || len(values) != 2 {
+		return nil, fmt.Errorf("unexpected result from sliding window script for key %v: %v", r.Key, res)
 	}
 
-	if err := add.Err(); err != nil {
-		return nil, fmt.Errorf("failed to add item to key %v: %w", r.Key, err)
+	allowed, ok := values[0].(int64)
+	if !ok {
+		return nil, fmt.Errorf("unexpected allowed type from sliding window script for key %v: %v", r.Key, values[0])
 	}
 
-	totalRequests, err := count.Result()
-	if err != nil {
-		return nil, fmt.Errorf("failed to count items for key %v: %w", r.Key, err)
+	count, ok := values[1].(int64)
+	if !ok {
+		return nil, fmt.Errorf("unexpected count type from sliding window script for key %v: %v", r.Key, values[1])
 	}
 
-	requests := uint64(totalRequests)
+	requests := uint64(count)
+
+	var remaining uint64
+	if requests < r.Limit {
+		remaining = r.Limit - requests
+	}
 
-	if requests > r.Limit {
+	if allowed == 0 {
 		return &rate_limiter_impl.Result{
 			State:         rate_limiter_impl.Deny,
 			TotalRequests: requests,
+			Remaining:     remaining,
 			ExpiresAt:     expiresAt,
 		}, nil
 	}
@@ -94,6 +104,7 @@ func (s *slidingWindowLimiter) Execute(ctx context.Context, r *rate_limiter_impl
 	return &rate_limiter_impl.Result{
 		State:         rate_limiter_impl.Allow,
 		TotalRequests: requests,
+		Remaining:     remaining,
 		ExpiresAt:     expiresAt,
 	}, nil
 }
@@ -30,6 +30,7 @@ func TestSlidingWindowLimiter_Execute(t *testing.T) {
 			res: &rate_limiter_impl.Result{
 				State:         rate_limiter_impl.Allow,
 				TotalRequests: 50,
+				Remaining:     50,
 				ExpiresAt:     time.Date(2024, time.June, 23, 10, 16, 30, 0, time.Local),
 			},
 			runs: 50,
@@ -45,6 +46,7 @@ func TestSlidingWindowLimiter_Execute(t *testing.T) {
 			res: &rate_limiter_impl.Result{
 				State:         rate_limiter_impl.Deny,
 				TotalRequests: 100,
+				Remaining:     0,
 				ExpiresAt:     time.Date(2024, time.June, 23, 10, 16, 30, 0, time.Local),
 			},
 			runs: 101,
@@ -60,6 +62,7 @@ func TestSlidingWindowLimiter_Execute(t *testing.T) {
 			res: &rate_limiter_impl.Result{
 				State:         rate_limiter_impl.Allow,
 				TotalRequests: 60,
+				Remaining:     40,
 				ExpiresAt:     time.Date(2024, time.June, 23, 10, 18, 9, 0, time.Local),
 			},
 			runs:        100,
@@ -2,14 +2,61 @@ package rate_limiting_strategies
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"github.com/aryangodara/rate_limiter_impl"
 	"github.com/redis/go-redis/v9"
-	"strconv"
 	"time"
 )
 
+// tokenBucketScript atomically refills and debits a token bucket: it reads
+// the current token count and last refill time, computes how many refill
+// intervals have elapsed, tops up the bucket, and - if enough tokens remain
+// - charges the cost, all in one round trip instead of the GET/GET,
+// compute-in-Go, SET/SET sequence this used to run.
+var tokenBucketScript = redis.NewScript(`
+local tokensKey = KEYS[1]
+local lastUpdateKey = KEYS[2]
+
+local now = tonumber(ARGV[1])
+local maxTokens = tonumber(ARGV[2])
+local refillTime = tonumber(ARGV[3])
+local refillAmount = tonumber(ARGV[4])
+local cost = tonumber(ARGV[5])
+
+local lastUpdate = tonumber(redis.call('GET', lastUpdateKey)) or 0
+local tokens = tonumber(redis.call('GET', tokensKey))
+if tokens == nil then
+	tokens = maxTokens
+end
+
+if lastUpdate > 0 then
+	local refillCount = math.floor((now - lastUpdate) / refillTime)
+	if refillCount > 0 then
+		tokens = tokens + refillCount * refillAmount
+		if tokens > maxTokens then
+			tokens = maxTokens
+		end
+		lastUpdate = now
+	end
+else
+	lastUpdate = now
+end
+
+local allowed = 0
+local resetTime = lastUpdate + refillTime
+
+if tokens >= cost then
+	tokens = tokens - cost
+	allowed = 1
+	resetTime = now + refillTime
+end
+
+redis.call('SET', tokensKey, tokens)
+redis.call('SET', lastUpdateKey, lastUpdate)
+
+return {allowed, tokens, resetTime}
+`)
+
 type tokenBucketLimiter struct {
 	client        *redis.Client
 	latRefillTime func() time.Time
@@ -34,77 +81,45 @@ func (t *tokenBucketLimiter) Execute(ctx context.Context, r *rate_limiter_impl.R
 	lastUpdateKey := r.Key + ":lastUpdate"
 	tokenCountKey := r.Key + ":tokens"
 
-	// Fetch last update time
-	lastUpdateStr, err := t.client.Get(ctx,
-		lastUpdateKey).Result()
-	if err != nil && !errors.Is(err, redis.Nil) {
-		return nil, fmt.Errorf("failed to get last update time: %w", err)
-	}
-
-	var lastUpdate int64
-	if lastUpdateStr != "" {
-		lastUpdate, err = strconv.ParseInt(lastUpdateStr, 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse last update time: %w", err)
-		}
+	res, err := tokenBucketScript.Run(ctx, t.client, []string{tokenCountKey, lastUpdateKey},
+		now, t.maxTokens, int64(t.refillTime.Seconds()), t.refillAmount, 1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error executing token bucket script for key %v: %w", r.Key, err)
 	}
 
-	// Fetch current token count
-	tokenCountStr, err := t.client.Get(ctx, tokenCountKey).Result()
-	if err != nil && !errors.Is(err, redis.Nil) {
-		return nil, fmt.Errorf("failed to get token count: %w", err)
+	values, ok := res.([]interface{})
+	if !ok || len(values) != 3 {
+		return nil, fmt.Errorf("unexpected result from token bucket script for key %v: %v", r.Key, res)
 	}
 
-	var tokenCount int64
-	if tokenCountStr != "" {
-		tokenCount, err = strconv.ParseInt(tokenCountStr, 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse token count: %w", err)
-		}
-	} else {
-		tokenCount = t.maxTokens
+	allowed, ok := values[0].(int64)
+	if !ok {
+		return nil, fmt.Errorf("unexpected allowed type from token bucket script for key %v: %v", r.Key, values[0])
 	}
 
-	// Calculate the number of tokens to refill
-	if lastUpdate > 0 {
-		refillCount := (now - lastUpdate) / int64(t.refillTime.Seconds())
-		if refillCount > 0 {
-			tokenCount += refillCount * t.refillAmount
-			if tokenCount > t.maxTokens {
-				tokenCount = t.maxTokens
-			}
-			lastUpdate = now
-		}
-	} else {
-		lastUpdate = now
+	remaining, ok := values[1].(int64)
+	if !ok {
+		return nil, fmt.Errorf("unexpected remaining type from token bucket script for key %v: %v", r.Key, values[1])
 	}
 
-	// Update tokens and last update time in Redis
-	p := t.client.Pipeline()
-	p.Set(ctx, tokenCountKey, tokenCount, 0)
-	p.Set(ctx, lastUpdateKey, lastUpdate, 0)
-	if _, err := p.Exec(ctx); err != nil {
-		return nil, fmt.Errorf("failed to update tokens and last update time: %w", err)
+	resetTime, ok := values[2].(int64)
+	if !ok {
+		return nil, fmt.Errorf("unexpected reset time type from token bucket script for key %v: %v", r.Key, values[2])
 	}
 
-	// Check if request can be allowed
-	if tokenCount > 0 {
-		tokenCount--
-		p.Set(ctx, tokenCountKey, tokenCount, 0)
-		if _, err := p.Exec(ctx); err != nil {
-			return nil, fmt.Errorf("failed to decrement token count: %w", err)
-		}
-
+	if allowed == 0 {
 		return &rate_limiter_impl.Result{
-			State:         rate_limiter_impl.Allow,
-			TotalRequests: uint64(tokenCount),
-			ExpiresAt:     time.Unix(now, 0).Add(t.refillTime),
+			State:         rate_limiter_impl.Deny,
+			TotalRequests: 0,
+			Remaining:     0,
+			ExpiresAt:     time.Unix(resetTime, 0),
 		}, nil
 	}
 
 	return &rate_limiter_impl.Result{
-		State:         rate_limiter_impl.Deny,
-		TotalRequests: 0,
-		ExpiresAt:     time.Unix(lastUpdate, 0).Add(t.refillTime),
+		State:         rate_limiter_impl.Allow,
+		TotalRequests: uint64(remaining),
+		Remaining:     uint64(remaining),
+		ExpiresAt:     time.Unix(resetTime, 0),
 	}, nil
 }
@@ -30,6 +30,7 @@ func TestTokenBucketLimiter_Execute(t *testing.T) {
 			res: &rate_limiter_impl.Result{
 				State:         rate_limiter_impl.Allow,
 				TotalRequests: 5,
+				Remaining:     5,
 				ExpiresAt:     time.Date(2024, time.June, 23, 10, 16, 30, 0, time.Local),
 			},
 			runs: 5,
@@ -45,6 +46,7 @@ func TestTokenBucketLimiter_Execute(t *testing.T) {
 			res: &rate_limiter_impl.Result{
 				State:         rate_limiter_impl.Deny,
 				TotalRequests: 0,
+				Remaining:     0,
 				ExpiresAt:     time.Date(2024, time.June, 23, 10, 16, 30, 0, time.Local),
 			},
 			runs: 11,
@@ -60,6 +62,7 @@ func TestTokenBucketLimiter_Execute(t *testing.T) {
 			res: &rate_limiter_impl.Result{
 				State:         rate_limiter_impl.Allow,
 				TotalRequests: 9,
+				Remaining:     9,
 				ExpiresAt:     time.Date(2024, time.June, 23, 10, 25, 30, 0, time.Local),
 			},
 			runs:        10,